@@ -0,0 +1,297 @@
+package ip
+
+import (
+  "iter"
+  "math/big"
+  "net"
+  "net/netip"
+  "sort"
+)
+
+func addrToInt(a netip.Addr) *big.Int {
+  return new(big.Int).SetBytes(a.AsSlice())
+}
+
+func intToAddr(i *big.Int, byteLen int) netip.Addr {
+  buf := make([]byte, byteLen)
+  b := i.Bytes()
+  if len(b) > byteLen {
+    b = b[len(b)-byteLen:]
+  }
+  copy(buf[byteLen-len(b):], b)
+  a, _ := netip.AddrFromSlice(buf)
+  return a
+}
+
+func maskAddr(a netip.Addr, bits int) netip.Addr {
+  return netip.PrefixFrom(a, bits).Masked().Addr()
+}
+
+func flipBit(a netip.Addr, bit int) netip.Addr {
+  buf := a.AsSlice()
+  buf[bit/8] ^= 1 << (7 - uint(bit%8))
+  flipped, _ := netip.AddrFromSlice(buf)
+  return flipped
+}
+
+// Subnets splits n into the equal-sized child networks of length
+// newPrefixLen. It yields nothing if newPrefixLen is narrower than n's
+// own prefix length or wider than the address family allows.
+func (n IP) Subnets(newPrefixLen int) iter.Seq[IP] {
+  return func(yield func(IP) bool) {
+    p, err := n.AsPrefix()
+    if err != nil {
+      return
+    }
+    bitLen := p.addr.addr.BitLen()
+    curBits := p.bits
+    if curBits < 0 {
+      curBits = bitLen
+    }
+    if newPrefixLen < curBits || newPrefixLen > bitLen {
+      return
+    }
+
+    byteLen := bitLen / 8
+    base := addrToInt(maskAddr(p.addr.addr, curBits))
+    step := new(big.Int).Lsh(big.NewInt(1), uint(bitLen-newPrefixLen))
+    count := new(big.Int).Lsh(big.NewInt(1), uint(newPrefixLen-curBits))
+
+    cur := new(big.Int).Set(base)
+    for i := big.NewInt(0); i.Cmp(count) < 0; i.Add(i, big.NewInt(1)) {
+      child := Prefix{
+        addr: Addr{addr: intToAddr(cur, byteLen), zone: p.addr.zone},
+        bits: newPrefixLen,
+      }
+      if !yield(FromPrefix(child)) {
+        return
+      }
+      cur.Add(cur, step)
+    }
+  }
+}
+
+// Supernet widens n by bits prefix positions (e.g. Supernet(1) turns a
+// /24 into a /23 containing it), clamped at a prefix length of 0.
+func (n IP) Supernet(bits int) IP {
+  p, err := n.AsPrefix()
+  if err != nil {
+    return n
+  }
+  curBits := p.bits
+  if curBits < 0 {
+    curBits = p.addr.addr.BitLen()
+  }
+  newBits := curBits - bits
+  if newBits < 0 {
+    newBits = 0
+  }
+  return FromPrefix(Prefix{
+    addr: Addr{addr: maskAddr(p.addr.addr, newBits), zone: p.addr.zone},
+    bits: newBits,
+  })
+}
+
+// Overlaps reports whether n and other share any address, on the same
+// address family.
+func (n IP) Overlaps(other IP) bool {
+  p1, err1 := n.AsPrefix()
+  p2, err2 := other.AsPrefix()
+  if err1 != nil || err2 != nil || p1.addr.addr.Is6() != p2.addr.addr.Is6() {
+    return false
+  }
+  return p1.netipPrefix().Overlaps(p2.netipPrefix())
+}
+
+// Hosts iterates n's usable host addresses, skipping the network and
+// broadcast addresses for an IPv4 network (IPv6 has no broadcast
+// address, so every address in the range is yielded). A /31 or /32 has
+// no distinct network/broadcast address to skip (RFC 3021), so every
+// address in the range is yielded for those too.
+func (n IP) Hosts() iter.Seq[net.IP] {
+  return func(yield func(net.IP) bool) {
+    p, err := n.AsPrefix()
+    if err != nil {
+      return
+    }
+    bits := p.bits
+    bitLen := p.addr.addr.BitLen()
+    if bits < 0 {
+      bits = bitLen
+    }
+    byteLen := bitLen / 8
+
+    first := addrToInt(maskAddr(p.addr.addr, bits))
+    last := new(big.Int).Add(first, new(big.Int).Sub(
+      new(big.Int).Lsh(big.NewInt(1), uint(bitLen-bits)), big.NewInt(1)))
+
+    if !p.addr.addr.Is6() && bits < bitLen-1 {
+      first.Add(first, big.NewInt(1))
+      last.Sub(last, big.NewInt(1))
+    }
+
+    for cur := new(big.Int).Set(first); cur.Cmp(last) <= 0; cur.Add(cur, big.NewInt(1)) {
+      if !yield(net.IP(intToAddr(cur, byteLen).AsSlice())) {
+        return
+      }
+    }
+  }
+}
+
+// First returns the lowest address in n's range (the network address,
+// for a network).
+func (n IP) First() IP {
+  p, err := n.AsPrefix()
+  if err != nil {
+    return n
+  }
+  bits := p.bits
+  if bits < 0 {
+    bits = p.addr.addr.BitLen()
+  }
+  return FromAddr(Addr{addr: maskAddr(p.addr.addr, bits), zone: p.addr.zone})
+}
+
+// Last returns the highest address in n's range (the broadcast address,
+// for an IPv4 network).
+func (n IP) Last() IP {
+  p, err := n.AsPrefix()
+  if err != nil {
+    return n
+  }
+  bits := p.bits
+  bitLen := p.addr.addr.BitLen()
+  if bits < 0 {
+    bits = bitLen
+  }
+  byteLen := bitLen / 8
+  last := new(big.Int).Add(addrToInt(maskAddr(p.addr.addr, bits)), new(big.Int).Sub(
+    new(big.Int).Lsh(big.NewInt(1), uint(bitLen-bits)), big.NewInt(1)))
+  return FromAddr(Addr{addr: intToAddr(last, byteLen), zone: p.addr.zone})
+}
+
+// NumAddresses returns the number of addresses covered by n.
+func (n IP) NumAddresses() *big.Int {
+  p, err := n.AsPrefix()
+  if err != nil {
+    return big.NewInt(0)
+  }
+  bits := p.bits
+  bitLen := p.addr.addr.BitLen()
+  if bits < 0 {
+    bits = bitLen
+  }
+  return new(big.Int).Lsh(big.NewInt(1), uint(bitLen-bits))
+}
+
+// Aggregate merges adjacent and overlapping prefixes in ns into the
+// minimal covering set of CIDR networks, per address family and zone.
+func Aggregate(ns []IP) []IP {
+  var v4, v6 []Prefix
+  for _, n := range ns {
+    p, err := n.AsPrefix()
+    if err != nil {
+      continue
+    }
+    if p.addr.addr.Is6() {
+      v6 = append(v6, p)
+    } else {
+      v4 = append(v4, p)
+    }
+  }
+
+  out := aggregateFamily(v4)
+  out = append(out, aggregateFamily(v6)...)
+
+  result := make([]IP, len(out))
+  for i, p := range out {
+    result[i] = FromPrefix(p)
+  }
+  return result
+}
+
+func aggregateFamily(entries []Prefix) []Prefix {
+  groups := map[string][]Prefix{}
+  for _, p := range entries {
+    groups[p.addr.zone] = append(groups[p.addr.zone], p)
+  }
+
+  var out []Prefix
+  for zone, g := range groups {
+    out = append(out, aggregateGroup(g, zone)...)
+  }
+  return out
+}
+
+type aggKey struct {
+  addr netip.Addr
+  bits int
+}
+
+// aggregateGroup merges same-zone, same-family prefixes: first dropping
+// any prefix already covered by a broader one, then repeatedly combining
+// sibling pairs (two prefixes of the same length differing only in their
+// last bit) into their shared parent, until a fixed point is reached.
+func aggregateGroup(entries []Prefix, zone string) []Prefix {
+  set := map[aggKey]bool{}
+  for _, p := range entries {
+    np := p.netipPrefix().Masked()
+    set[aggKey{np.Addr(), np.Bits()}] = true
+  }
+
+  for changed := true; changed; {
+    changed = false
+
+    keys := make([]aggKey, 0, len(set))
+    for k := range set {
+      keys = append(keys, k)
+    }
+    sort.Slice(keys, func(i, j int) bool { return keys[i].bits < keys[j].bits })
+
+    for _, broad := range keys {
+      if !set[broad] {
+        continue
+      }
+      for _, k := range keys {
+        if k == broad || !set[k] || k.bits <= broad.bits {
+          continue
+        }
+        if maskAddr(k.addr, broad.bits) == broad.addr {
+          delete(set, k)
+          changed = true
+        }
+      }
+    }
+
+    keys = keys[:0]
+    for k := range set {
+      keys = append(keys, k)
+    }
+    for _, k := range keys {
+      if !set[k] || k.bits == 0 {
+        continue
+      }
+      sibling := aggKey{flipBit(k.addr, k.bits-1), k.bits}
+      if set[sibling] {
+        parent := aggKey{maskAddr(k.addr, k.bits-1), k.bits - 1}
+        delete(set, k)
+        delete(set, sibling)
+        set[parent] = true
+        changed = true
+      }
+    }
+  }
+
+  result := make([]Prefix, 0, len(set))
+  for k := range set {
+    result = append(result, Prefix{addr: Addr{addr: k.addr, zone: zone}, bits: k.bits})
+  }
+  sort.Slice(result, func(i, j int) bool {
+    bi, bj := addrToInt(result[i].addr.addr), addrToInt(result[j].addr.addr)
+    if c := bi.Cmp(bj); c != 0 {
+      return c < 0
+    }
+    return result[i].bits < result[j].bits
+  })
+  return result
+}
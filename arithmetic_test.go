@@ -0,0 +1,111 @@
+package ip
+
+import (
+  "math/big"
+  "slices"
+  "testing"
+)
+
+func TestSubnets(t *testing.T) {
+  n, _ := Parse("192.168.0.0/24")
+  var got []string
+  for sub := range n.Subnets(26) {
+    got = append(got, sub.String())
+  }
+  want := []string{
+    "192.168.0.0/26",
+    "192.168.0.64/26",
+    "192.168.0.128/26",
+    "192.168.0.192/26",
+  }
+  if !slices.Equal(got, want) {
+    t.Errorf("Subnets(26) = %v, want %v", got, want)
+  }
+}
+
+func TestSupernet(t *testing.T) {
+  n, _ := Parse("192.168.1.0/24")
+  if s := n.Supernet(1).String(); s != "192.168.0.0/23" {
+    t.Errorf("Supernet(1) = %q, want 192.168.0.0/23", s)
+  }
+}
+
+func TestOverlaps(t *testing.T) {
+  a, _ := Parse("10.0.0.0/8")
+  b, _ := Parse("10.1.0.0/16")
+  c, _ := Parse("11.0.0.0/8")
+  if !a.Overlaps(b) {
+    t.Errorf("10.0.0.0/8 should overlap 10.1.0.0/16")
+  }
+  if a.Overlaps(c) {
+    t.Errorf("10.0.0.0/8 should not overlap 11.0.0.0/8")
+  }
+}
+
+func TestHostsSkipsNetworkAndBroadcast(t *testing.T) {
+  n, _ := Parse("192.168.1.0/30")
+  var got []string
+  for h := range n.Hosts() {
+    got = append(got, h.String())
+  }
+  want := []string{"192.168.1.1", "192.168.1.2"}
+  if !slices.Equal(got, want) {
+    t.Errorf("Hosts() = %v, want %v", got, want)
+  }
+}
+
+func TestHostsPointToPointLink(t *testing.T) {
+  n, _ := Parse("192.168.1.0/31")
+  var got []string
+  for h := range n.Hosts() {
+    got = append(got, h.String())
+  }
+  want := []string{"192.168.1.0", "192.168.1.1"}
+  if !slices.Equal(got, want) {
+    t.Errorf("Hosts() on /31 = %v, want %v", got, want)
+  }
+}
+
+func TestFirstLast(t *testing.T) {
+  n, _ := Parse("192.168.1.0/24")
+  if s := n.First().String(); s != "192.168.1.0" {
+    t.Errorf("First() = %q, want 192.168.1.0", s)
+  }
+  if s := n.Last().String(); s != "192.168.1.255" {
+    t.Errorf("Last() = %q, want 192.168.1.255", s)
+  }
+}
+
+func TestNumAddresses(t *testing.T) {
+  n, _ := Parse("192.168.0.0/24")
+  if got := n.NumAddresses(); got.Cmp(big.NewInt(256)) != 0 {
+    t.Errorf("NumAddresses() = %v, want 256", got)
+  }
+}
+
+func TestAggregate(t *testing.T) {
+  parse := func(s string) IP {
+    n, err := Parse(s)
+    if err != nil {
+      t.Fatalf("Parse(%q): %v", s, err)
+    }
+    return n
+  }
+
+  in := []IP{
+    parse("192.168.0.0/25"),
+    parse("192.168.0.128/25"),
+    parse("10.0.0.0/8"),
+  }
+  out := Aggregate(in)
+
+  var got []string
+  for _, n := range out {
+    got = append(got, n.String())
+  }
+  slices.Sort(got)
+  want := []string{"10.0.0.0/8", "192.168.0.0/24"}
+  if !slices.Equal(got, want) {
+    t.Errorf("Aggregate() = %v, want %v", got, want)
+  }
+}
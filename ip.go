@@ -1,9 +1,9 @@
 package ip
 
 import (
-  "bytes"
   "errors"
   "net"
+  "net/netip"
   "strings"
 )
 
@@ -19,13 +19,19 @@ const (
   ZoneSep = "%"
 )
 
-func ParseZone(s string) (ip, zone string, err error) {
+// ParseZone splits s into its address part and zone, per RFC 4007. The
+// zone, if present, must be non-empty and is either an interface name or
+// a numeric scope id; see ValidateZoneInterface to additionally require
+// that an interface name actually exist.
+func ParseZone(s string, opts ...ParseOption) (ip, zone string, err error) {
+  o := applyOptions(opts)
   parts := strings.Split(s, ZoneSep)
   switch len(parts) {
   case 1:
     ip, zone = s, NoZone
   case 2:
     ip, zone = parts[0], parts[1]
+    err = validateZone(zone, o)
   default:
     err = errors.New("IP may contain only one '%': <ip>%<zone>")
   }
@@ -42,8 +48,11 @@ func ParseZone(s string) (ip, zone string, err error) {
    IPv6 net w/o zone   2001:DB8::/48
    IPv6 net w/ zone    2001:DB8::/48%eth0
 */
-func Parse(s string) (r IP, err error) {
-  s, r.Zone, err = ParseZone(s)
+// The IPv4 forms with a zone parse only when AllowIPv4Zone is passed.
+func Parse(s string, opts ...ParseOption) (r IP, err error) {
+  o := applyOptions(opts)
+
+  s, r.Zone, err = ParseZone(s, opts...)
   if err != nil {
     return
   }
@@ -53,12 +62,16 @@ func Parse(s string) (r IP, err error) {
   if ipnet != nil { // we're done
     r.IP = ipnet.IP
     r.Mask = ipnet.Mask
-    return
+  } else {
+    r.IP = net.ParseIP(s)
+    if r.IP == nil {
+      err = errors.New("Bad IPv4/v6 IP addr or network")
+      return
+    }
   }
 
-  r.IP = net.ParseIP(s)
-  if r.IP == nil {
-    err = errors.New("Bad IPv4/v6 IP addr or network")
+  if r.HasZone() && r.IP.To4() != nil && !o.allowIPv4Zone {
+    err = errors.New("zones on IPv4 addresses are non-standard; see AllowIPv4Zone")
     return
   }
 
@@ -66,9 +79,12 @@ func Parse(s string) (r IP, err error) {
 }
 
 func (n IP) Equal(n2 IP) bool {
-  return n.EqualZone(n2.Zone) &&
-    n.IP.Equal(n2.IP) &&
-    bytes.Compare(n.Mask, n2.Mask) == 0
+  p, err := n.AsPrefix()
+  p2, err2 := n2.AsPrefix()
+  if err != nil || err2 != nil {
+    return false
+  }
+  return p.Equal(p2)
 }
 
 func (n IP) IsIPv6() bool {
@@ -76,16 +92,13 @@ func (n IP) IsIPv6() bool {
 }
 
 func (n IP) IsIPv4() bool {
-  return n.IP.To4() != nil
+  a, err := n.AsAddr()
+  return err == nil && a.IsIPv4()
 }
 
 func (n IP) IsNetwork() bool {
-  for _, v := range n.Mask {
-    if v != 0xff {
-      return true
-    }
-  }
-  return false
+  p, err := n.AsPrefix()
+  return err == nil && p.IsNetwork()
 }
 
 func (n IP) HasZone() bool {
@@ -100,6 +113,15 @@ func (n IP) EqualInterface(iface *net.Interface) bool {
   return iface == nil || n.EqualZone(iface.Name)
 }
 
+// zoneAppliesTo reports whether zone scopes something reachable via
+// iface: an empty zone applies to any interface; otherwise the names
+// must agree. Unlike EqualInterface, iface is never nil here - this is
+// for callers walking candidate interfaces for a given zone, not the
+// other way around.
+func zoneAppliesTo(zone string, iface *net.Interface) bool {
+  return zone == NoZone || zone == iface.Name
+}
+
 // no zone = all interfaces
 func (n IP) Interfaces() (ifaces []net.Interface) {
   if !n.HasZone() {
@@ -125,7 +147,18 @@ func (n IP) IPAddr() net.IPAddr {
 
 // iface: nil = any interface
 func (n IP) ContainsWithInterface(ip net.IP, iface *net.Interface) bool {
-  return n.EqualInterface(iface) && (n.IP.Equal(ip) || n.IPNet().Contains(ip))
+  if !n.EqualInterface(iface) {
+    return false
+  }
+  p, err := n.AsPrefix()
+  if err != nil {
+    return false
+  }
+  a, ok := netip.AddrFromSlice(ip)
+  if !ok {
+    return false
+  }
+  return p.Contains(a)
 }
 
 // any interface is allowed
@@ -138,13 +171,19 @@ func (n IP) Network() string {
 }
 
 func (n IP) String() (s string) {
-  if n.Mask == nil {
-    s = n.IP.String()
-  } else {
-    s = n.IPNet().String()
-  }
-  if n.HasZone() {
-    s += ZoneSep + n.Zone
+  p, err := n.AsPrefix()
+  if err != nil {
+    // n.IP didn't round-trip through netip (e.g. nil or a malformed
+    // slice); fall back to the legacy net.IP-based rendering.
+    if n.Mask == nil {
+      s = n.IP.String()
+    } else {
+      s = n.IPNet().String()
+    }
+    if n.HasZone() {
+      s += ZoneSep + canonicalZone(n.Zone)
+    }
+    return
   }
-  return
+  return p.String()
 }
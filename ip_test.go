@@ -47,11 +47,13 @@ var mustNotParse = []string{
   "::1z%eth0",
   "2001:DB8::/48%%",
   "2001:DB8::/48%eth0%",
+  "1.2.3.4%",
+  "::1%",
 }
 
 func TestParse(t *testing.T) {
   for _, validCase := range mustParse {
-    ipn, err := Parse(validCase.x)
+    ipn, err := Parse(validCase.x, AllowIPv4Zone())
     if err != nil {
       t.Errorf("Parse(\"%s\") failed (should parse)", validCase.x)
     }
@@ -74,7 +76,7 @@ func TestParse(t *testing.T) {
       }
     }
     if !ipn.EqualZone(validCase.zone) {
-      t.Errorf("Parse(\"%s\") failed (bad zone %s != %s) %s", validCase.x, ipn.Zone, validCase.zone)
+      t.Errorf("Parse(%q) failed (bad zone %q != %q)", validCase.x, ipn.Zone, validCase.zone)
     }
   }
 
@@ -99,12 +101,9 @@ func TestContains(t *testing.T) {
 
 func TestInterfaces(t *testing.T) {
   x, _ := Parse("80.0.0.0/8")
-  ifaces, err := x.Interfaces()
-  if err != nil {
-    t.Errorf("Interfaces() failed, err=", err)
-  }
+  ifaces := x.Interfaces()
   for _, iface := range ifaces {
-    t.Logf("Interface: iface=", iface)
+    t.Logf("Interface: iface=%v", iface)
   }
 }
 
@@ -117,7 +116,7 @@ func TestNetwork(t *testing.T) {
 
 func TestString(t *testing.T) {
   for _, stringCase := range stringCases {
-    j, _ := Parse(stringCase.x)
+    j, _ := Parse(stringCase.x, AllowIPv4Zone())
     if s := j.String(); strings.ToUpper(s) != strings.ToUpper(stringCase.x) {
       t.Errorf("String() failed (%s != %s)", s, stringCase.x)
     }
@@ -0,0 +1,110 @@
+package ip
+
+import (
+  "bufio"
+  "encoding/json"
+  "io"
+  "strings"
+)
+
+// MarshalText implements encoding.TextMarshaler.
+func (n IP) MarshalText() ([]byte, error) {
+  return []byte(n.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. It accepts whatever
+// String produces, including a zone on an IPv4 address, since that's a
+// value this package itself can emit.
+func (n *IP) UnmarshalText(data []byte) error {
+  parsed, err := Parse(string(data), AllowIPv4Zone())
+  if err != nil {
+    return err
+  }
+  *n = parsed
+  return nil
+}
+
+// MarshalJSON implements json.Marshaler, encoding n as its String form.
+func (n IP) MarshalJSON() ([]byte, error) {
+  return json.Marshal(n.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (n *IP) UnmarshalJSON(data []byte) error {
+  var s string
+  if err := json.Unmarshal(data, &s); err != nil {
+    return err
+  }
+  return n.UnmarshalText([]byte(s))
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler, encoding n as its
+// String form.
+func (n IP) MarshalBinary() ([]byte, error) {
+  return n.MarshalText()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (n *IP) UnmarshalBinary(data []byte) error {
+  return n.UnmarshalText(data)
+}
+
+// Scanner reads one address or network per line from an allow/deny list,
+// skipping blank lines and "#" comments (whole-line or trailing).
+type Scanner struct {
+  sc   *bufio.Scanner
+  opts []ParseOption
+  cur  IP
+  err  error
+}
+
+// NewScanner returns a Scanner reading from r. opts are passed to Parse
+// for each line.
+func NewScanner(r io.Reader, opts ...ParseOption) *Scanner {
+  return &Scanner{sc: bufio.NewScanner(r), opts: opts}
+}
+
+// Scan advances to the next entry, reporting whether one was found. Call
+// IP to retrieve it, or Err once Scan returns false to check for a parse
+// or read error.
+func (s *Scanner) Scan() bool {
+  for s.sc.Scan() {
+    line := s.sc.Text()
+    if i := strings.IndexByte(line, '#'); i >= 0 {
+      line = line[:i]
+    }
+    line = strings.TrimSpace(line)
+    if line == "" {
+      continue
+    }
+    n, err := Parse(line, s.opts...)
+    if err != nil {
+      s.err = err
+      return false
+    }
+    s.cur = n
+    return true
+  }
+  s.err = s.sc.Err()
+  return false
+}
+
+// IP returns the entry produced by the most recent call to Scan.
+func (s *Scanner) IP() IP { return s.cur }
+
+// Err returns the first error encountered by Scan, if any.
+func (s *Scanner) Err() error { return s.err }
+
+// ParseAll reads every address/network line from r, in the Scanner
+// format, and returns them in order.
+func ParseAll(r io.Reader, opts ...ParseOption) ([]IP, error) {
+  var result []IP
+  sc := NewScanner(r, opts...)
+  for sc.Scan() {
+    result = append(result, sc.IP())
+  }
+  if err := sc.Err(); err != nil {
+    return nil, err
+  }
+  return result, nil
+}
@@ -0,0 +1,95 @@
+package ip
+
+import (
+  "encoding/json"
+  "strings"
+  "testing"
+)
+
+func TestTextMarshalRoundTrip(t *testing.T) {
+  for _, x := range []string{"192.168.0.0/16", "::1%eth0", "fe80::1%3"} {
+    n, err := Parse(x)
+    if err != nil {
+      t.Fatalf("Parse(%q): %v", x, err)
+    }
+    data, err := n.MarshalText()
+    if err != nil {
+      t.Fatalf("MarshalText(%q): %v", x, err)
+    }
+    var back IP
+    if err := back.UnmarshalText(data); err != nil {
+      t.Fatalf("UnmarshalText(%q): %v", data, err)
+    }
+    if !back.Equal(n) || back.String() != n.String() {
+      t.Errorf("round trip %q -> %q -> %q", x, data, back.String())
+    }
+  }
+}
+
+func TestJSONMarshalRoundTrip(t *testing.T) {
+  type config struct {
+    Net IP `json:"net"`
+  }
+  n, _ := Parse("10.0.0.0/8")
+  c := config{Net: n}
+
+  data, err := json.Marshal(c)
+  if err != nil {
+    t.Fatalf("json.Marshal: %v", err)
+  }
+  if !strings.Contains(string(data), `"10.0.0.0/8"`) {
+    t.Errorf("json.Marshal = %s, want it to contain \"10.0.0.0/8\"", data)
+  }
+
+  var back config
+  if err := json.Unmarshal(data, &back); err != nil {
+    t.Fatalf("json.Unmarshal: %v", err)
+  }
+  if !back.Net.Equal(n) {
+    t.Errorf("json round trip = %v, want %v", back.Net, n)
+  }
+}
+
+func TestBinaryMarshalRoundTrip(t *testing.T) {
+  n, _ := Parse("2001:DB8::/48")
+  data, err := n.MarshalBinary()
+  if err != nil {
+    t.Fatalf("MarshalBinary: %v", err)
+  }
+  var back IP
+  if err := back.UnmarshalBinary(data); err != nil {
+    t.Fatalf("UnmarshalBinary: %v", err)
+  }
+  if !back.Equal(n) {
+    t.Errorf("binary round trip = %v, want %v", back, n)
+  }
+}
+
+func TestParseAll(t *testing.T) {
+  const list = `
+# allow list
+10.0.0.0/8
+192.168.1.1 # trusted host
+
+::1
+`
+  entries, err := ParseAll(strings.NewReader(list))
+  if err != nil {
+    t.Fatalf("ParseAll: %v", err)
+  }
+  if len(entries) != 3 {
+    t.Fatalf("ParseAll returned %d entries, want 3: %v", len(entries), entries)
+  }
+  want := []string{"10.0.0.0/8", "192.168.1.1", "::1"}
+  for i, w := range want {
+    if entries[i].String() != w {
+      t.Errorf("entries[%d] = %q, want %q", i, entries[i].String(), w)
+    }
+  }
+}
+
+func TestParseAllError(t *testing.T) {
+  if _, err := ParseAll(strings.NewReader("not-an-ip\n")); err == nil {
+    t.Errorf("ParseAll should fail on a malformed line")
+  }
+}
@@ -0,0 +1,169 @@
+package ip
+
+import (
+  "errors"
+  "net"
+  "net/netip"
+  "strconv"
+)
+
+// Addr is IP's address-plus-zone half as a comparable, allocation-free
+// value built on net/netip.Addr, safe to use as a map key. Unlike
+// netip.Addr, it allows a zone on IPv4 addresses too (see AllowIPv4Zone).
+type Addr struct {
+  addr netip.Addr
+  zone string
+}
+
+// ParseAddr parses an address with an optional zone, e.g. "1.2.3.4",
+// "::1", or "::1%eth0". Unlike Parse, it rejects CIDR notation.
+func ParseAddr(s string, opts ...ParseOption) (a Addr, err error) {
+  o := applyOptions(opts)
+  s, zone, err := ParseZone(s, opts...)
+  if err != nil {
+    return
+  }
+  na, err := netip.ParseAddr(s)
+  if err != nil {
+    err = errors.New("Bad IPv4/v6 IP addr")
+    return
+  }
+  na = na.Unmap()
+  if zone != NoZone && na.Is4() && !o.allowIPv4Zone {
+    err = errors.New("zones on IPv4 addresses are non-standard; see AllowIPv4Zone")
+    return
+  }
+  a = Addr{addr: na, zone: zone}
+  return
+}
+
+func (a Addr) Zone() string    { return a.zone }
+func (a Addr) HasZone() bool   { return a.zone != NoZone }
+func (a Addr) IsValid() bool   { return a.addr.IsValid() }
+func (a Addr) IsIPv4() bool    { return a.addr.Is4() }
+func (a Addr) IsIPv6() bool    { return !a.IsIPv4() }
+func (a Addr) Netip() netip.Addr { return a.addr }
+
+func (a Addr) Equal(a2 Addr) bool {
+  return a.zone == a2.zone && a.addr == a2.addr
+}
+
+func (a Addr) String() (s string) {
+  s = a.addr.String()
+  if a.HasZone() {
+    s += ZoneSep + canonicalZone(a.zone)
+  }
+  return
+}
+
+// Prefix is the netip-backed counterpart of IP: a single address or a
+// CIDR network, optionally zoned, as a comparable value type usable
+// directly as a map key.
+type Prefix struct {
+  addr Addr
+  bits int // -1 = single address (no explicit prefix length)
+}
+
+// ParsePrefix parses the same grammar as Parse (address or CIDR network,
+// optionally zoned) directly into the netip-backed Prefix.
+func ParsePrefix(s string, opts ...ParseOption) (p Prefix, err error) {
+  o := applyOptions(opts)
+  s, zone, err := ParseZone(s, opts...)
+  if err != nil {
+    return
+  }
+
+  var addr netip.Addr
+  bits := -1
+  if np, perr := netip.ParsePrefix(s); perr == nil {
+    addr, bits = np.Addr().Unmap(), np.Bits()
+  } else if na, aerr := netip.ParseAddr(s); aerr == nil {
+    addr = na.Unmap()
+  } else {
+    err = errors.New("Bad IPv4/v6 IP addr or network")
+    return
+  }
+
+  if zone != NoZone && addr.Is4() && !o.allowIPv4Zone {
+    err = errors.New("zones on IPv4 addresses are non-standard; see AllowIPv4Zone")
+    return
+  }
+  p = Prefix{addr: Addr{addr: addr, zone: zone}, bits: bits}
+  return
+}
+
+func (p Prefix) Addr() Addr { return p.addr }
+
+// IsNetwork reports whether p carries an explicit prefix length narrower
+// than a full-width host address.
+func (p Prefix) IsNetwork() bool {
+  return p.bits >= 0 && p.bits < p.addr.addr.BitLen()
+}
+
+func (p Prefix) netipPrefix() netip.Prefix {
+  bits := p.bits
+  if bits < 0 {
+    bits = p.addr.addr.BitLen()
+  }
+  return netip.PrefixFrom(p.addr.addr, bits)
+}
+
+// Contains reports whether a is within p; it does not check zones.
+func (p Prefix) Contains(a netip.Addr) bool {
+  return p.netipPrefix().Contains(a.Unmap())
+}
+
+func (p Prefix) Equal(p2 Prefix) bool {
+  return p.addr.Equal(p2.addr) && p.bits == p2.bits
+}
+
+func (p Prefix) String() (s string) {
+  s = p.addr.addr.String()
+  if p.bits >= 0 {
+    s += "/" + strconv.Itoa(p.bits)
+  }
+  if p.addr.HasZone() {
+    s += ZoneSep + canonicalZone(p.addr.zone)
+  }
+  return
+}
+
+// AsAddr converts n to the netip-backed Addr, discarding any CIDR mask.
+func (n IP) AsAddr() (Addr, error) {
+  na, ok := netip.AddrFromSlice(n.IP)
+  if !ok {
+    return Addr{}, errors.New("invalid IP")
+  }
+  return Addr{addr: na.Unmap(), zone: n.Zone}, nil
+}
+
+// FromAddr builds an IP from the netip-backed Addr.
+func FromAddr(a Addr) IP {
+  return IP{IP: a.addr.AsSlice(), Zone: a.zone}
+}
+
+// AsPrefix converts n to the netip-backed Prefix, preserving its mask.
+func (n IP) AsPrefix() (Prefix, error) {
+  a, err := n.AsAddr()
+  if err != nil {
+    return Prefix{}, err
+  }
+  bits := -1
+  if n.Mask != nil {
+    ones, size := n.Mask.Size()
+    if ones == 0 && size == 0 {
+      return Prefix{}, errors.New("Bad IPv4/v6 mask: not contiguous")
+    }
+    bits = ones
+  }
+  return Prefix{addr: a, bits: bits}, nil
+}
+
+// FromPrefix builds an IP from the netip-backed Prefix.
+func FromPrefix(p Prefix) IP {
+  n := FromAddr(p.addr)
+  if p.bits >= 0 {
+    n.Mask = net.CIDRMask(p.bits, p.addr.addr.BitLen())
+  }
+  return n
+}
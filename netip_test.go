@@ -0,0 +1,72 @@
+package ip
+
+import (
+  "net"
+  "testing"
+)
+
+func TestAddrRoundTrip(t *testing.T) {
+  for _, x := range []string{"4.5.6.7", "1.2.3.4%lo0", "::1", "::1%eth0"} {
+    a, err := ParseAddr(x, AllowIPv4Zone())
+    if err != nil {
+      t.Errorf("ParseAddr(%q) failed: %v", x, err)
+      continue
+    }
+    if s := a.String(); s != x {
+      t.Errorf("ParseAddr(%q).String() = %q", x, s)
+    }
+  }
+}
+
+func TestPrefixRoundTrip(t *testing.T) {
+  for _, x := range []string{"192.168.0.0/16", "192.168.0.0/16%eth0", "80.1.2.3"} {
+    p, err := ParsePrefix(x, AllowIPv4Zone())
+    if err != nil {
+      t.Errorf("ParsePrefix(%q) failed: %v", x, err)
+      continue
+    }
+    if s := p.String(); s != x {
+      t.Errorf("ParsePrefix(%q).String() = %q", x, s)
+    }
+  }
+}
+
+func TestIPAsPrefixRoundTrip(t *testing.T) {
+  n, _ := Parse("80.0.0.0/8%eth0", AllowIPv4Zone())
+  p, err := n.AsPrefix()
+  if err != nil {
+    t.Fatalf("AsPrefix() failed: %v", err)
+  }
+  if got, want := p.String(), n.String(); got != want {
+    t.Errorf("AsPrefix round trip = %q, want %q", got, want)
+  }
+  if back := FromPrefix(p); !back.Equal(n) {
+    t.Errorf("FromPrefix(AsPrefix(n)) = %v, want %v", back, n)
+  }
+}
+
+func TestAsPrefixRejectsNonCanonicalMask(t *testing.T) {
+  n := IP{IP: net.ParseIP("1.2.3.4"), Mask: net.IPMask{0xff, 0, 0xff, 0}}
+  if _, err := n.AsPrefix(); err == nil {
+    t.Errorf("AsPrefix() should reject a non-contiguous mask")
+  }
+  if n.Contains(net.ParseIP("9.9.9.9")) {
+    t.Errorf("Contains() should not match every address for a non-contiguous mask")
+  }
+}
+
+func TestAddrAsMapKey(t *testing.T) {
+  a, _ := ParseAddr("1.2.3.4")
+  b, _ := ParseAddr("1.2.3.4")
+  m := map[Addr]bool{a: true}
+  if !m[b] {
+    t.Errorf("Addr is not usable as a comparable map key")
+  }
+}
+
+func TestContainsStillWorks(t *testing.T) {
+  x, _ := Parse("80.0.0.0/8")
+  if !x.Contains(net.ParseIP("80.1.2.3")) {
+    t.Errorf("Contains() fails after netip migration")
+  }
+}
@@ -0,0 +1,75 @@
+package ip
+
+import (
+  "errors"
+  "net"
+)
+
+// ResolveInterface finds the local interface whose assigned address most
+// specifically contains n.IP (longest-prefix match). If n has a zone,
+// only that interface is considered.
+func (n IP) ResolveInterface() (*net.Interface, error) {
+  ifaces, err := net.Interfaces()
+  if err != nil {
+    return nil, err
+  }
+
+  var best *net.Interface
+  bestOnes := -1
+  for i := range ifaces {
+    iface := ifaces[i]
+    if !zoneAppliesTo(n.Zone, &iface) {
+      continue
+    }
+    addrs, err := iface.Addrs()
+    if err != nil {
+      continue
+    }
+    for _, a := range addrs {
+      ipnet, ok := a.(*net.IPNet)
+      if !ok || !ipnet.Contains(n.IP) {
+        continue
+      }
+      if ones, _ := ipnet.Mask.Size(); ones > bestOnes {
+        bestOnes, best = ones, &iface
+      }
+    }
+  }
+  if best == nil {
+    return nil, errors.New("No local interface route to " + n.String())
+  }
+  return best, nil
+}
+
+// LocalAddrFor returns the source address the OS would use to reach dst,
+// via a UDP dial trick (no packet is sent); it falls back to
+// ResolveInterface if that fails.
+func LocalAddrFor(dst net.IP) (IP, error) {
+  network := "udp4"
+  if dst.To4() == nil {
+    network = "udp6"
+  }
+
+  if conn, err := net.Dial(network, net.JoinHostPort(dst.String(), "9")); err == nil {
+    defer conn.Close()
+    if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+      return IP{IP: addr.IP}, nil
+    }
+  }
+
+  dstIP := IP{IP: dst}
+  iface, err := dstIP.ResolveInterface()
+  if err != nil {
+    return IP{}, err
+  }
+  addrs, err := iface.Addrs()
+  if err != nil {
+    return IP{}, err
+  }
+  for _, a := range addrs {
+    if ipnet, ok := a.(*net.IPNet); ok && ipnet.Contains(dst) {
+      return IP{IP: ipnet.IP}, nil
+    }
+  }
+  return IP{}, errors.New("No local address found for " + dstIP.String())
+}
@@ -0,0 +1,23 @@
+package ip
+
+import (
+  "net"
+  "testing"
+)
+
+func TestResolveInterfaceLoopback(t *testing.T) {
+  n, _ := Parse("127.0.0.1")
+  iface, err := n.ResolveInterface()
+  if err != nil {
+    t.Fatalf("ResolveInterface() failed: %v", err)
+  }
+  t.Logf("127.0.0.1 resolves to interface %s", iface.Name)
+}
+
+func TestLocalAddrForLoopback(t *testing.T) {
+  addr, err := LocalAddrFor(net.ParseIP("127.0.0.1"))
+  if err != nil {
+    t.Fatalf("LocalAddrFor() failed: %v", err)
+  }
+  t.Logf("local address for 127.0.0.1 is %s", addr)
+}
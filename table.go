@@ -0,0 +1,251 @@
+package ip
+
+import (
+  "net"
+  "sort"
+)
+
+// tableNode is one node of a binary radix trie; a node holds a value
+// only when some inserted prefix ends exactly there.
+type tableNode[V any] struct {
+  children [2]*tableNode[V]
+  hasValue bool
+  prefix   Prefix
+  value    V
+}
+
+// tableRoot holds one zone's trie root; map values aren't addressable in
+// Go, so this indirection lets descend/deleteAt take &root.node.
+type tableRoot[V any] struct {
+  node *tableNode[V]
+}
+
+// Table is a binary trie over IPv4 and IPv6 prefixes supporting
+// longest-prefix-match lookup in O(bits) time. Each zone gets its own
+// trie, so the same prefix can be inserted once per zone.
+//
+// The zero value is ready to use.
+type Table[V any] struct {
+  v4   map[string]*tableRoot[V] // keyed by zone (NoZone for unzoned entries)
+  v6   map[string]*tableRoot[V]
+  size int
+}
+
+// NewTable returns an empty Table.
+func NewTable[V any]() *Table[V] {
+  return &Table[V]{}
+}
+
+func bitAt(b []byte, i int) int {
+  return int(b[i/8]>>(7-uint(i%8))) & 1
+}
+
+func (t *Table[V]) family(v6 bool) *map[string]*tableRoot[V] {
+  if v6 {
+    return &t.v6
+  }
+  return &t.v4
+}
+
+func (t *Table[V]) getOrCreateRoot(v6 bool, zone string) *tableRoot[V] {
+  m := t.family(v6)
+  if *m == nil {
+    *m = map[string]*tableRoot[V]{}
+  }
+  root := (*m)[zone]
+  if root == nil {
+    root = &tableRoot[V]{}
+    (*m)[zone] = root
+  }
+  return root
+}
+
+// descend walks bits levels down bytes from *cur, creating nodes as
+// needed, and returns the node slot at that depth.
+func descend[V any](cur **tableNode[V], bytes []byte, bits int) **tableNode[V] {
+  for depth := 0; depth < bits; depth++ {
+    if *cur == nil {
+      *cur = &tableNode[V]{}
+    }
+    cur = &(*cur).children[bitAt(bytes, depth)]
+  }
+  return cur
+}
+
+// Insert adds key (an address or CIDR network, optionally zoned) with the
+// given value, replacing any value previously inserted for the same
+// prefix and zone.
+func (t *Table[V]) Insert(key IP, value V) error {
+  p, err := key.AsPrefix()
+  if err != nil {
+    return err
+  }
+  np := p.netipPrefix().Masked()
+  bytes := np.Addr().AsSlice()
+
+  root := t.getOrCreateRoot(p.Addr().IsIPv6(), p.Addr().Zone())
+  slot := descend(&root.node, bytes, np.Bits())
+  if *slot == nil {
+    *slot = &tableNode[V]{}
+  }
+  if !(*slot).hasValue {
+    t.size++
+  }
+  (*slot).hasValue = true
+  (*slot).prefix = p
+  (*slot).value = value
+  return nil
+}
+
+// Delete removes key, reporting whether it was present. It does not
+// affect narrower or wider prefixes, or the same prefix in other zones.
+func (t *Table[V]) Delete(key IP) bool {
+  p, err := key.AsPrefix()
+  if err != nil {
+    return false
+  }
+  np := p.netipPrefix().Masked()
+  bytes := np.Addr().AsSlice()
+
+  m := *t.family(p.Addr().IsIPv6())
+  root := m[p.Addr().Zone()]
+  if root == nil {
+    return false
+  }
+  removed := deleteAt(&root.node, bytes, np.Bits(), 0)
+  if removed {
+    t.size--
+  }
+  return removed
+}
+
+func deleteAt[V any](n **tableNode[V], bytes []byte, bits, depth int) bool {
+  if *n == nil {
+    return false
+  }
+  var removed bool
+  if depth == bits {
+    removed = (*n).hasValue
+    (*n).hasValue = false
+    var zero V
+    (*n).value = zero
+  } else {
+    bit := bitAt(bytes, depth)
+    removed = deleteAt(&(*n).children[bit], bytes, bits, depth+1)
+  }
+  if !(*n).hasValue && (*n).children[0] == nil && (*n).children[1] == nil {
+    *n = nil
+  }
+  return removed
+}
+
+// Lookup finds the most specific (longest-prefix-match) entry containing
+// addr, considering entries in every zone.
+func (t *Table[V]) Lookup(addr net.IP) (IP, V, bool) {
+  return t.LookupWithInterface(addr, nil)
+}
+
+// LookupWithInterface is Lookup scoped to iface: an unzoned entry always
+// matches, a zoned entry matches only an iface of the same name, and
+// passing a nil iface matches entries in every zone.
+func (t *Table[V]) LookupWithInterface(addr net.IP, iface *net.Interface) (result IP, value V, ok bool) {
+  a, aok := addrBytes(addr)
+  if !aok {
+    return
+  }
+  m := *t.family(len(a) == 16)
+
+  var best *tableNode[V]
+  bestBits := -1
+  search := func(root *tableRoot[V]) {
+    n := root.node
+    for depth := 0; n != nil; depth++ {
+      if n.hasValue && depth > bestBits {
+        best, bestBits = n, depth
+      }
+      if depth >= len(a)*8 {
+        return
+      }
+      n = n.children[bitAt(a, depth)]
+    }
+  }
+
+  if iface == nil {
+    for _, root := range m {
+      search(root)
+    }
+  } else {
+    if root := m[NoZone]; root != nil {
+      search(root)
+    }
+    if iface.Name != NoZone {
+      if root := m[iface.Name]; root != nil {
+        search(root)
+      }
+    }
+  }
+
+  if best == nil {
+    return
+  }
+  return FromPrefix(best.prefix), best.value, true
+}
+
+// Contains reports whether any entry, in any zone, contains addr.
+func (t *Table[V]) Contains(addr net.IP) bool {
+  _, _, ok := t.Lookup(addr)
+  return ok
+}
+
+// Len returns the number of distinct (prefix, zone) entries currently
+// stored.
+func (t *Table[V]) Len() int {
+  return t.size
+}
+
+// Walk calls fn for every entry in prefix order within each zone (zones
+// visited in sorted order, v4 before v6, each trie depth-first with the
+// 0 branch before the 1 branch). It stops early if fn returns false.
+func (t *Table[V]) Walk(fn func(IP, V) bool) {
+  if !walkFamily(t.v4, fn) {
+    return
+  }
+  walkFamily(t.v6, fn)
+}
+
+func walkFamily[V any](m map[string]*tableRoot[V], fn func(IP, V) bool) bool {
+  zones := make([]string, 0, len(m))
+  for zone := range m {
+    zones = append(zones, zone)
+  }
+  sort.Strings(zones)
+  for _, zone := range zones {
+    if !walk(m[zone].node, fn) {
+      return false
+    }
+  }
+  return true
+}
+
+func walk[V any](n *tableNode[V], fn func(IP, V) bool) bool {
+  if n == nil {
+    return true
+  }
+  if n.hasValue && !fn(FromPrefix(n.prefix), n.value) {
+    return false
+  }
+  if !walk(n.children[0], fn) {
+    return false
+  }
+  return walk(n.children[1], fn)
+}
+
+func addrBytes(ip net.IP) ([]byte, bool) {
+  if v4 := ip.To4(); v4 != nil {
+    return v4, true
+  }
+  if v6 := ip.To16(); v6 != nil {
+    return v6, true
+  }
+  return nil, false
+}
@@ -0,0 +1,177 @@
+package ip
+
+import (
+  "fmt"
+  "net"
+  "testing"
+)
+
+func TestTableLookup(t *testing.T) {
+  var tbl Table[string]
+
+  mustInsert := func(s, v string) {
+    n, err := Parse(s)
+    if err != nil {
+      t.Fatalf("Parse(%q): %v", s, err)
+    }
+    if err := tbl.Insert(n, v); err != nil {
+      t.Fatalf("Insert(%q): %v", s, err)
+    }
+  }
+
+  mustInsert("10.0.0.0/8", "ten")
+  mustInsert("10.1.0.0/16", "ten-one")
+  mustInsert("10.1.2.0/24", "ten-one-two")
+  mustInsert("::/0", "v6-default")
+
+  cases := []struct {
+    addr string
+    want string
+  }{
+    {"10.1.2.3", "ten-one-two"},
+    {"10.1.3.3", "ten-one"},
+    {"10.2.3.3", "ten"},
+    {"::1", "v6-default"},
+  }
+  for _, c := range cases {
+    _, v, ok := tbl.Lookup(net.ParseIP(c.addr))
+    if !ok || v != c.want {
+      t.Errorf("Lookup(%q) = %q, %v, want %q", c.addr, v, ok, c.want)
+    }
+  }
+
+  if _, _, ok := tbl.Lookup(net.ParseIP("192.168.1.1")); ok {
+    t.Errorf("Lookup(192.168.1.1) should miss")
+  }
+
+  if !tbl.Delete(mustParseIP(t, "10.1.2.0/24")) {
+    t.Errorf("Delete(10.1.2.0/24) should report removal")
+  }
+  _, v, ok := tbl.Lookup(net.ParseIP("10.1.2.3"))
+  if !ok || v != "ten-one" {
+    t.Errorf("after delete, Lookup(10.1.2.3) = %q, %v, want ten-one", v, ok)
+  }
+}
+
+func TestTableZoneScoping(t *testing.T) {
+  var tbl Table[bool]
+  tbl.Insert(mustParseIP(t, "169.254.0.0/16%eth0", AllowIPv4Zone()), true)
+
+  if _, _, ok := tbl.LookupWithInterface(net.ParseIP("169.254.1.1"), &net.Interface{Name: "eth1"}); ok {
+    t.Errorf("LookupWithInterface should not match a different interface")
+  }
+  if _, _, ok := tbl.LookupWithInterface(net.ParseIP("169.254.1.1"), &net.Interface{Name: "eth0"}); !ok {
+    t.Errorf("LookupWithInterface should match the same interface")
+  }
+  if _, _, ok := tbl.Lookup(net.ParseIP("169.254.1.1")); !ok {
+    t.Errorf("Lookup (any interface) should match a zoned entry")
+  }
+}
+
+func TestTableZoneCollision(t *testing.T) {
+  var tbl Table[string]
+  tbl.Insert(mustParseIP(t, "169.254.0.0/16%eth0", AllowIPv4Zone()), "eth0")
+  tbl.Insert(mustParseIP(t, "169.254.0.0/16%eth1", AllowIPv4Zone()), "eth1")
+
+  if tbl.Len() != 2 {
+    t.Errorf("Len() = %d, want 2 (same prefix, different zones)", tbl.Len())
+  }
+
+  _, v, ok := tbl.LookupWithInterface(net.ParseIP("169.254.1.1"), &net.Interface{Name: "eth0"})
+  if !ok || v != "eth0" {
+    t.Errorf("LookupWithInterface(eth0) = %q, %v, want eth0", v, ok)
+  }
+  _, v, ok = tbl.LookupWithInterface(net.ParseIP("169.254.1.1"), &net.Interface{Name: "eth1"})
+  if !ok || v != "eth1" {
+    t.Errorf("LookupWithInterface(eth1) = %q, %v, want eth1", v, ok)
+  }
+}
+
+func TestTableUnzonedMatchesAnyInterface(t *testing.T) {
+  var tbl Table[string]
+  tbl.Insert(mustParseIP(t, "10.0.0.0/8"), "unzoned")
+
+  _, v, ok := tbl.LookupWithInterface(net.ParseIP("10.1.2.3"), &net.Interface{Name: "eth0"})
+  if !ok || v != "unzoned" {
+    t.Errorf("LookupWithInterface should see an unzoned entry regardless of interface, got %q, %v", v, ok)
+  }
+}
+
+func TestTableWalk(t *testing.T) {
+  var tbl Table[int]
+  tbl.Insert(mustParseIP(t, "1.0.0.0/8"), 1)
+  tbl.Insert(mustParseIP(t, "2.0.0.0/8"), 2)
+
+  seen := map[string]int{}
+  tbl.Walk(func(n IP, v int) bool {
+    seen[n.String()] = v
+    return true
+  })
+  if len(seen) != 2 || seen["1.0.0.0/8"] != 1 || seen["2.0.0.0/8"] != 2 {
+    t.Errorf("Walk produced %v", seen)
+  }
+}
+
+func mustParseIP(t *testing.T, s string, opts ...ParseOption) IP {
+  t.Helper()
+  n, err := Parse(s, opts...)
+  if err != nil {
+    t.Fatalf("Parse(%q): %v", s, err)
+  }
+  return n
+}
+
+// naiveSet is the linear-scan baseline Table is meant to replace.
+type naiveSet []IP
+
+func (s naiveSet) lookup(addr net.IP) (IP, bool) {
+  var best IP
+  bestOnes := -1
+  for _, n := range s {
+    if !n.Contains(addr) {
+      continue
+    }
+    ones, _ := n.Mask.Size()
+    if ones > bestOnes {
+      best, bestOnes = n, ones
+    }
+  }
+  return best, bestOnes >= 0
+}
+
+func buildEntries(n int) ([]IP, net.IP) {
+  entries := make([]IP, 0, n)
+  for i := 0; i < n; i++ {
+    a, b, c := byte(i>>16), byte(i>>8), byte(i)
+    ip, _ := Parse(fmt.Sprintf("10.%d.%d.%d/32", a, b, c))
+    entries = append(entries, ip)
+  }
+  return entries, net.ParseIP("10.0.0.1")
+}
+
+func BenchmarkTableLookup(b *testing.B) {
+  for _, n := range []int{10_000, 100_000, 1_000_000} {
+    entries, probe := buildEntries(n)
+    var tbl Table[struct{}]
+    for _, e := range entries {
+      tbl.Insert(e, struct{}{})
+    }
+    b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+      for i := 0; i < b.N; i++ {
+        tbl.Contains(probe)
+      }
+    })
+  }
+}
+
+func BenchmarkNaiveSliceLookup(b *testing.B) {
+  for _, n := range []int{10_000, 100_000, 1_000_000} {
+    entries, probe := buildEntries(n)
+    set := naiveSet(entries)
+    b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+      for i := 0; i < b.N; i++ {
+        set.lookup(probe)
+      }
+    })
+  }
+}
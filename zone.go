@@ -0,0 +1,100 @@
+package ip
+
+import (
+  "errors"
+  "fmt"
+  "net"
+  "strconv"
+)
+
+// parseOptions controls the RFC 4007 zone handling performed by ParseZone,
+// Parse, ParseAddr, and ParsePrefix.
+type parseOptions struct {
+  allowIPv4Zone         bool
+  validateZoneInterface bool
+}
+
+// ParseOption adjusts the behavior of Parse, ParseAddr, and ParsePrefix.
+type ParseOption func(*parseOptions)
+
+// AllowIPv4Zone permits a zone on an IPv4 address or network. This package
+// has historically allowed it (e.g. "1.2.3.4%eth0"), but RFC 4007 defines
+// zones for IPv6 only, so callers now opt in explicitly.
+func AllowIPv4Zone() ParseOption {
+  return func(o *parseOptions) { o.allowIPv4Zone = true }
+}
+
+// ValidateZoneInterface additionally requires that a non-numeric zone name
+// resolve via net.InterfaceByName, rejecting typos and stale interface
+// names up front instead of failing later at use.
+func ValidateZoneInterface() ParseOption {
+  return func(o *parseOptions) { o.validateZoneInterface = true }
+}
+
+func applyOptions(opts []ParseOption) parseOptions {
+  var o parseOptions
+  for _, opt := range opts {
+    opt(&o)
+  }
+  return o
+}
+
+// zoneIndex reports whether zone is a numeric RFC 4007 scope id
+// (sll_ifindex), as opposed to an interface name.
+func zoneIndex(zone string) (int, bool) {
+  n, err := strconv.Atoi(zone)
+  if err != nil || n < 0 {
+    return 0, false
+  }
+  return n, true
+}
+
+// canonicalZone rewrites a numeric zone to its canonical decimal form
+// (e.g. "007" -> "7"), matching how the kernel reports scope ids.
+// Interface-name zones are returned unchanged.
+func canonicalZone(zone string) string {
+  if n, ok := zoneIndex(zone); ok {
+    return strconv.Itoa(n)
+  }
+  return zone
+}
+
+func validateZone(zone string, o parseOptions) error {
+  if zone == NoZone {
+    return errors.New("Zone must not be empty")
+  }
+  if _, ok := zoneIndex(zone); ok {
+    return nil
+  }
+  if o.validateZoneInterface {
+    if _, err := net.InterfaceByName(zone); err != nil {
+      return fmt.Errorf("Zone %q is not a known interface: %w", zone, err)
+    }
+  }
+  return nil
+}
+
+// ZoneIndex resolves n's zone to an interface index, per RFC 4007. A
+// numeric zone (e.g. "%2") is returned as-is; an interface-name zone
+// (e.g. "%eth0") is resolved via net.InterfaceByName. It returns an error
+// if n has no zone.
+func (n IP) ZoneIndex() (int, error) {
+  if !n.HasZone() {
+    return 0, errors.New("IP has no zone")
+  }
+  if idx, ok := zoneIndex(n.Zone); ok {
+    return idx, nil
+  }
+  iface, err := net.InterfaceByName(n.Zone)
+  if err != nil {
+    return 0, err
+  }
+  return iface.Index, nil
+}
+
+// FromInterface returns n with its zone set to iface's name, scoping it
+// to that interface.
+func FromInterface(iface net.Interface, n IP) IP {
+  n.Zone = iface.Name
+  return n
+}
@@ -0,0 +1,61 @@
+package ip
+
+import (
+  "net"
+  "testing"
+)
+
+func TestZoneRejectsEmpty(t *testing.T) {
+  if _, err := Parse("1.2.3.4%"); err == nil {
+    t.Errorf("Parse(\"1.2.3.4%%\") should fail on an empty zone")
+  }
+}
+
+func TestZoneRejectsIPv4ByDefault(t *testing.T) {
+  if _, err := Parse("1.2.3.4%eth0"); err == nil {
+    t.Errorf("Parse(\"1.2.3.4%%eth0\") should fail without AllowIPv4Zone")
+  }
+  if _, err := Parse("1.2.3.4%eth0", AllowIPv4Zone()); err != nil {
+    t.Errorf("Parse(\"1.2.3.4%%eth0\", AllowIPv4Zone()) failed: %v", err)
+  }
+}
+
+func TestZoneNumericCanonicalization(t *testing.T) {
+  n, err := Parse("::1%007")
+  if err != nil {
+    t.Fatalf("Parse failed: %v", err)
+  }
+  if s := n.String(); s != "::1%7" {
+    t.Errorf("String() = %q, want \"::1%%7\"", s)
+  }
+}
+
+func TestZoneIndexNumeric(t *testing.T) {
+  n, _ := Parse("::1%3")
+  idx, err := n.ZoneIndex()
+  if err != nil || idx != 3 {
+    t.Errorf("ZoneIndex() = %d, %v, want 3, nil", idx, err)
+  }
+}
+
+func TestZoneIndexNoZone(t *testing.T) {
+  n, _ := Parse("::1")
+  if _, err := n.ZoneIndex(); err == nil {
+    t.Errorf("ZoneIndex() on an unzoned address should fail")
+  }
+}
+
+func TestFromInterface(t *testing.T) {
+  n, _ := Parse("fe80::1")
+  iface := net.Interface{Name: "eth0"}
+  zoned := FromInterface(iface, n)
+  if zoned.Zone != "eth0" {
+    t.Errorf("FromInterface zone = %q, want eth0", zoned.Zone)
+  }
+}
+
+func TestValidateZoneInterfaceRejectsUnknown(t *testing.T) {
+  if _, err := Parse("fe80::1%definitely-not-a-real-interface", ValidateZoneInterface()); err == nil {
+    t.Errorf("Parse with ValidateZoneInterface should reject an unknown interface name")
+  }
+}